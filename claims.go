@@ -0,0 +1,338 @@
+package paseto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Registered claim names, as defined by the PASETO specification.
+// https://github.com/paseto-standard/paseto-spec/blob/master/docs/02-Implementation-Guide/04-Claims.md
+const (
+	claimIssuer     = "iss"
+	claimSubject    = "sub"
+	claimAudience   = "aud"
+	claimExpiration = "exp"
+	claimNotBefore  = "nbf"
+	claimIssuedAt   = "iat"
+	claimTokenID    = "jti"
+)
+
+// Errors returned by Validator checks and by DecryptJSONToken/VerifyJSONToken.
+var (
+	ErrTokenExpired     = errors.New("paseto: token is expired")
+	ErrTokenNotYetValid = errors.New("paseto: token is not yet valid")
+	ErrInvalidAudience  = errors.New("paseto: invalid audience")
+	ErrInvalidIssuer    = errors.New("paseto: invalid issuer")
+	ErrInvalidSubject   = errors.New("paseto: invalid subject")
+	ErrInvalidToken     = errors.New("paseto: invalid token")
+)
+
+// JSONToken is a PASETO payload holding the registered claims defined by the
+// PASETO specification, plus arbitrary custom claims. The zero value is an
+// empty token.
+type JSONToken struct {
+	Issuer     string
+	Subject    string
+	Audience   string
+	Expiration time.Time
+	NotBefore  time.Time
+	IssuedAt   time.Time
+	TokenID    string
+
+	custom map[string]json.RawMessage
+}
+
+// Set adds a custom claim to t, overwriting any existing claim with the
+// same name. name must not be one of the registered claim names above.
+func (t *JSONToken) Set(name string, value interface{}) error {
+	if isRegisteredClaim(name) {
+		return fmt.Errorf("paseto: %q is a registered claim name", name)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if t.custom == nil {
+		t.custom = make(map[string]json.RawMessage)
+	}
+	t.custom[name] = raw
+	return nil
+}
+
+// Get unmarshals the custom claim named name into value.
+func (t *JSONToken) Get(name string, value interface{}) error {
+	raw, ok := t.custom[name]
+	if !ok {
+		return fmt.Errorf("paseto: no claim named %q", name)
+	}
+	return json.Unmarshal(raw, value)
+}
+
+func isRegisteredClaim(name string) bool {
+	switch name {
+	case claimIssuer, claimSubject, claimAudience, claimExpiration, claimNotBefore, claimIssuedAt, claimTokenID:
+		return true
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler. Registered claims with a zero value
+// are omitted; time-valued claims are encoded per RFC 3339, as required by
+// the PASETO specification.
+func (t JSONToken) MarshalJSON() ([]byte, error) {
+	m := make(map[string]json.RawMessage, len(t.custom)+7)
+	for name, raw := range t.custom {
+		m[name] = raw
+	}
+	set := func(name string, v interface{}) error {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		m[name] = raw
+		return nil
+	}
+	if t.Issuer != "" {
+		if err := set(claimIssuer, t.Issuer); err != nil {
+			return nil, err
+		}
+	}
+	if t.Subject != "" {
+		if err := set(claimSubject, t.Subject); err != nil {
+			return nil, err
+		}
+	}
+	if t.Audience != "" {
+		if err := set(claimAudience, t.Audience); err != nil {
+			return nil, err
+		}
+	}
+	if t.TokenID != "" {
+		if err := set(claimTokenID, t.TokenID); err != nil {
+			return nil, err
+		}
+	}
+	if !t.Expiration.IsZero() {
+		if err := set(claimExpiration, t.Expiration.Format(time.RFC3339)); err != nil {
+			return nil, err
+		}
+	}
+	if !t.NotBefore.IsZero() {
+		if err := set(claimNotBefore, t.NotBefore.Format(time.RFC3339)); err != nil {
+			return nil, err
+		}
+	}
+	if !t.IssuedAt.IsZero() {
+		if err := set(claimIssuedAt, t.IssuedAt.Format(time.RFC3339)); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *JSONToken) UnmarshalJSON(data []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	str := func(name string, dst *string) error {
+		raw, ok := m[name]
+		if !ok {
+			return nil
+		}
+		delete(m, name)
+		return json.Unmarshal(raw, dst)
+	}
+	tm := func(name string, dst *time.Time) error {
+		raw, ok := m[name]
+		if !ok {
+			return nil
+		}
+		delete(m, name)
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		parsed, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return err
+		}
+		*dst = parsed
+		return nil
+	}
+	if err := str(claimIssuer, &t.Issuer); err != nil {
+		return err
+	}
+	if err := str(claimSubject, &t.Subject); err != nil {
+		return err
+	}
+	if err := str(claimAudience, &t.Audience); err != nil {
+		return err
+	}
+	if err := str(claimTokenID, &t.TokenID); err != nil {
+		return err
+	}
+	if err := tm(claimExpiration, &t.Expiration); err != nil {
+		return err
+	}
+	if err := tm(claimNotBefore, &t.NotBefore); err != nil {
+		return err
+	}
+	if err := tm(claimIssuedAt, &t.IssuedAt); err != nil {
+		return err
+	}
+	if len(m) > 0 {
+		t.custom = m
+	}
+	return nil
+}
+
+// Validator checks a decoded JSONToken against a set of expectations, such
+// as audience, issuer, and expiry. Build one with the With* methods, then
+// pass it to DecryptJSONToken or VerifyJSONToken.
+type Validator struct {
+	checks []func(*JSONToken) error
+}
+
+// WithAudience adds a check that the token's audience claim equals aud.
+func (v *Validator) WithAudience(aud string) *Validator {
+	v.checks = append(v.checks, func(t *JSONToken) error { return ValidateAudience(t, aud) })
+	return v
+}
+
+// WithIssuer adds a check that the token's issuer claim equals iss.
+func (v *Validator) WithIssuer(iss string) *Validator {
+	v.checks = append(v.checks, func(t *JSONToken) error { return ValidateIssuer(t, iss) })
+	return v
+}
+
+// WithSubject adds a check that the token's subject claim equals sub.
+func (v *Validator) WithSubject(sub string) *Validator {
+	v.checks = append(v.checks, func(t *JSONToken) error {
+		if t.Subject != sub {
+			return ErrInvalidSubject
+		}
+		return nil
+	})
+	return v
+}
+
+// WithNotExpired adds a check that the token's expiration claim, if set, is
+// after now.
+func (v *Validator) WithNotExpired(now time.Time) *Validator {
+	v.checks = append(v.checks, func(t *JSONToken) error {
+		if !t.Expiration.IsZero() && !now.Before(t.Expiration) {
+			return ErrTokenExpired
+		}
+		return nil
+	})
+	return v
+}
+
+// WithValidAt adds a check that now falls within the token's not-before and
+// expiration claims, for whichever of the two are set.
+func (v *Validator) WithValidAt(now time.Time) *Validator {
+	v.checks = append(v.checks, func(t *JSONToken) error {
+		if !t.NotBefore.IsZero() && now.Before(t.NotBefore) {
+			return ErrTokenNotYetValid
+		}
+		if !t.Expiration.IsZero() && !now.Before(t.Expiration) {
+			return ErrTokenExpired
+		}
+		return nil
+	})
+	return v
+}
+
+// Validate runs every check added to v, in the order they were added,
+// returning the first error encountered.
+func (v *Validator) Validate(t *JSONToken) error {
+	for _, check := range v.checks {
+		if err := check(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateAudience reports whether t's audience claim equals aud.
+func ValidateAudience(t *JSONToken, aud string) error {
+	if t.Audience != aud {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+// ValidateIssuer reports whether t's issuer claim equals iss.
+func ValidateIssuer(t *JSONToken, iss string) error {
+	if t.Issuer != iss {
+		return ErrInvalidIssuer
+	}
+	return nil
+}
+
+// EncryptJSONToken marshals token to JSON and encrypts it as a v2.local
+// token, as Encrypt does for an arbitrary payload.
+func EncryptJSONToken(key SymmetricKey, token *JSONToken, footer []byte) ([]byte, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return nil, err
+	}
+	return Encrypt(payload, key, footer), nil
+}
+
+// DecryptJSONToken decrypts a v2.local token produced by EncryptJSONToken
+// and unmarshals its payload. If v is non-nil, the resulting token is
+// checked against v before being returned. opts is passed through to
+// Decrypt, so e.g. ExpectFooter can be used to bind the token to an
+// expected footer.
+func DecryptJSONToken(tok []byte, key SymmetricKey, v *Validator, opts ...Option) (*JSONToken, []byte, error) {
+	payload, footer, ok := Decrypt(tok, key, opts...)
+	if !ok {
+		return nil, nil, ErrInvalidToken
+	}
+	var token JSONToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return nil, nil, err
+	}
+	if v != nil {
+		if err := v.Validate(&token); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &token, footer, nil
+}
+
+// SignJSONToken marshals token to JSON and signs it as a v2.public token, as
+// Sign does for an arbitrary payload.
+func SignJSONToken(token *JSONToken, privateKey AsymmetricSecretKey, footer []byte) ([]byte, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return nil, err
+	}
+	return Sign(payload, privateKey, footer)
+}
+
+// VerifyJSONToken verifies a v2.public token produced by SignJSONToken and
+// unmarshals its payload. If v is non-nil, the resulting token is checked
+// against v before being returned. opts is passed through to Verify, so
+// e.g. ExpectFooter can be used to bind the token to an expected footer.
+func VerifyJSONToken(tok []byte, publicKey AsymmetricPublicKey, v *Validator, opts ...Option) (*JSONToken, []byte, error) {
+	payload, footer, ok := Verify(tok, publicKey, opts...)
+	if !ok {
+		return nil, nil, ErrInvalidToken
+	}
+	var token JSONToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return nil, nil, err
+	}
+	if v != nil {
+		if err := v.Validate(&token); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &token, footer, nil
+}
@@ -0,0 +1,111 @@
+package paseto
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEncryptDecryptJSONToken(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	token := &JSONToken{
+		Issuer:     "paseto.example",
+		Subject:    "user-1",
+		Audience:   "paseto.example.api",
+		IssuedAt:   now,
+		NotBefore:  now,
+		Expiration: now.Add(time.Hour),
+	}
+	if err := token.Set("role", "admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := EncryptJSONToken(key, token, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := new(Validator).WithIssuer("paseto.example").WithAudience("paseto.example.api").WithValidAt(now.Add(time.Minute))
+	got, _, err := DecryptJSONToken(tok, key, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Subject != token.Subject {
+		t.Errorf("Subject = %q, want %q", got.Subject, token.Subject)
+	}
+	if !got.Expiration.Equal(token.Expiration) {
+		t.Errorf("Expiration = %v, want %v", got.Expiration, token.Expiration)
+	}
+	var role string
+	if err := got.Get("role", &role); err != nil {
+		t.Fatal(err)
+	}
+	if role != "admin" {
+		t.Errorf("role = %q, want %q", role, "admin")
+	}
+}
+
+func TestDecryptJSONToken_Expired(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	token := &JSONToken{Expiration: now.Add(-time.Hour)}
+	tok, err := EncryptJSONToken(key, token, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := new(Validator).WithNotExpired(now)
+	if _, _, err := DecryptJSONToken(tok, key, v); err != ErrTokenExpired {
+		t.Fatalf("err = %v, want %v", err, ErrTokenExpired)
+	}
+}
+
+func TestDecryptJSONToken_WrongAudience(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := &JSONToken{Audience: "paseto.example.api"}
+	tok, err := EncryptJSONToken(key, token, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := new(Validator).WithAudience("someone.else")
+	if _, _, err := DecryptJSONToken(tok, key, v); err != ErrInvalidAudience {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidAudience)
+	}
+}
+
+func TestDecryptJSONToken_ExpectFooter(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := &JSONToken{Subject: "user-1"}
+	tok, err := EncryptJSONToken(key, token, []byte("key-id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := DecryptJSONToken(tok, key, nil, ExpectFooter([]byte("wrong"))); err != ErrInvalidToken {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidToken)
+	}
+	got, _, err := DecryptJSONToken(tok, key, nil, ExpectFooter([]byte("key-id")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Subject != token.Subject {
+		t.Errorf("Subject = %q, want %q", got.Subject, token.Subject)
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"bytes"
 )
 
-var (
-	nonce = bytes.Repeat([]byte("-"), 24)
-	key   = bytes.Repeat([]byte("*"), 32)
-)
+var nonce = bytes.Repeat([]byte("-"), 24)
+
+var key = func() SymmetricKey {
+	var k SymmetricKey
+	copy(k[:], bytes.Repeat([]byte("*"), 32))
+	return k
+}()
 
 func Fuzz(data []byte) int {
 	// Avoid pounding on the OSCSPRNG, and increase reproducibility.
@@ -19,10 +22,7 @@ func Fuzz(data []byte) int {
 	if len(data) > 0 && data[0]%2 == 1 {
 		footer = data
 	}
-	token, err := Encrypt(payload, key, footer)
-	if err != nil {
-		panic(err)
-	}
+	token := Encrypt(payload, key, footer)
 	p, f, ok := Decrypt(token, key)
 	if !ok {
 		panic("round trip failed")
@@ -0,0 +1,37 @@
+package paseto
+
+import "crypto/ed25519"
+
+// SymmetricKey is a key for v2.local Encrypt and Decrypt. Its fixed size
+// guarantees that it is valid input to the underlying AEAD, so callers no
+// longer need to handle a key-length error from Encrypt.
+type SymmetricKey [32]byte
+
+// NewSymmetricKey generates a new random SymmetricKey using the OS's CSPRNG.
+func NewSymmetricKey() (SymmetricKey, error) {
+	var k SymmetricKey
+	if _, err := randRead(k[:]); err != nil {
+		return SymmetricKey{}, err
+	}
+	return k, nil
+}
+
+// AsymmetricSecretKey is an Ed25519 private key for v2.public Sign.
+type AsymmetricSecretKey [64]byte
+
+// AsymmetricPublicKey is an Ed25519 public key for v2.public Verify.
+type AsymmetricPublicKey [32]byte
+
+// GenerateAsymmetricKey generates a new random Ed25519 key pair for
+// v2.public Sign and Verify.
+func GenerateAsymmetricKey() (AsymmetricPublicKey, AsymmetricSecretKey, error) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return AsymmetricPublicKey{}, AsymmetricSecretKey{}, err
+	}
+	var pk AsymmetricPublicKey
+	var sk AsymmetricSecretKey
+	copy(pk[:], pub)
+	copy(sk[:], priv)
+	return pk, sk, nil
+}
@@ -0,0 +1,42 @@
+package paseto
+
+import "crypto/subtle"
+
+// Option configures optional behavior of Decrypt, Verify, DecryptJSONToken,
+// and VerifyJSONToken.
+type Option func(*options)
+
+type options struct {
+	footer      []byte
+	checkFooter bool
+}
+
+func parseOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// ExpectFooter returns an Option that makes Decrypt or Verify check,
+// using a constant-time comparison, that the token's footer matches footer
+// exactly. If the footer does not match, Decrypt and Verify report failure,
+// even if the token is otherwise valid.
+//
+// This is the spec-blessed way to bind a token to a footer-carried value,
+// such as a key ID or issuer.
+func ExpectFooter(footer []byte) Option {
+	return func(o *options) {
+		o.footer = footer
+		o.checkFooter = true
+	}
+}
+
+// check reports whether footer matches the footer expected by o, if any.
+func (o options) check(footer []byte) bool {
+	if !o.checkFooter {
+		return true
+	}
+	return subtle.ConstantTimeCompare(footer, o.footer) == 1
+}
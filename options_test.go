@@ -0,0 +1,36 @@
+package paseto
+
+import "testing"
+
+func TestDecrypt_ExpectFooter(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := Encrypt([]byte("payload"), key, []byte("key-id:1"))
+
+	if _, _, ok := Decrypt(token, key, ExpectFooter([]byte("key-id:1"))); !ok {
+		t.Fatal("expected success with matching footer")
+	}
+	if _, _, ok := Decrypt(token, key, ExpectFooter([]byte("key-id:2"))); ok {
+		t.Fatal("expected failure with mismatched footer")
+	}
+}
+
+func TestVerify_ExpectFooter(t *testing.T) {
+	publicKey, privateKey, err := GenerateAsymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := Sign([]byte("payload"), privateKey, []byte("key-id:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, ok := Verify(token, publicKey, ExpectFooter([]byte("key-id:1"))); !ok {
+		t.Fatal("expected success with matching footer")
+	}
+	if _, _, ok := Verify(token, publicKey, ExpectFooter([]byte("key-id:2"))); ok {
+		t.Fatal("expected failure with mismatched footer")
+	}
+}
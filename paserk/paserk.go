@@ -0,0 +1,114 @@
+// Package paserk implements PASERK, the key-serialization companion
+// standard to PASETO, for the k2 (v2) key types used by this module.
+//
+// See https://github.com/paseto-standard/paserk for details.
+package paserk
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/josharian/paseto"
+)
+
+const (
+	localHeader  = "k2.local."
+	publicHeader = "k2.public."
+	secretHeader = "k2.secret."
+
+	localIDHeader  = "k2.lid."
+	publicIDHeader = "k2.pid."
+	secretIDHeader = "k2.sid."
+)
+
+// Local serializes a v2.local symmetric key as a k2.local PASERK.
+func Local(k paseto.SymmetricKey) string {
+	return localHeader + base64.RawURLEncoding.EncodeToString(k[:])
+}
+
+// ParseLocal parses a k2.local PASERK produced by Local.
+func ParseLocal(s string) (paseto.SymmetricKey, error) {
+	raw, err := parse(localHeader, s, len(paseto.SymmetricKey{}))
+	if err != nil {
+		return paseto.SymmetricKey{}, err
+	}
+	var k paseto.SymmetricKey
+	copy(k[:], raw)
+	return k, nil
+}
+
+// Public serializes a v2.public Ed25519 public key as a k2.public PASERK.
+func Public(k paseto.AsymmetricPublicKey) string {
+	return publicHeader + base64.RawURLEncoding.EncodeToString(k[:])
+}
+
+// ParsePublic parses a k2.public PASERK produced by Public.
+func ParsePublic(s string) (paseto.AsymmetricPublicKey, error) {
+	raw, err := parse(publicHeader, s, len(paseto.AsymmetricPublicKey{}))
+	if err != nil {
+		return paseto.AsymmetricPublicKey{}, err
+	}
+	var k paseto.AsymmetricPublicKey
+	copy(k[:], raw)
+	return k, nil
+}
+
+// Secret serializes a v2.public Ed25519 private key as a k2.secret PASERK.
+func Secret(k paseto.AsymmetricSecretKey) string {
+	return secretHeader + base64.RawURLEncoding.EncodeToString(k[:])
+}
+
+// ParseSecret parses a k2.secret PASERK produced by Secret.
+func ParseSecret(s string) (paseto.AsymmetricSecretKey, error) {
+	raw, err := parse(secretHeader, s, len(paseto.AsymmetricSecretKey{}))
+	if err != nil {
+		return paseto.AsymmetricSecretKey{}, err
+	}
+	var k paseto.AsymmetricSecretKey
+	copy(k[:], raw)
+	return k, nil
+}
+
+func parse(header, s string, size int) ([]byte, error) {
+	if !strings.HasPrefix(s, header) {
+		return nil, fmt.Errorf("paserk: %q does not have expected header %q", s, header)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s[len(header):])
+	if err != nil {
+		return nil, fmt.Errorf("paserk: %w", err)
+	}
+	if len(raw) != size {
+		return nil, fmt.Errorf("paserk: key is %d bytes, want %d", len(raw), size)
+	}
+	return raw, nil
+}
+
+// LocalID returns the k2.lid key ID for k: a stable, non-secret identifier
+// suitable for use in a footer to indicate which key a token was sealed
+// with.
+func LocalID(k paseto.SymmetricKey) string {
+	return keyID(localIDHeader, Local(k))
+}
+
+// PublicID returns the k2.pid key ID for k.
+func PublicID(k paseto.AsymmetricPublicKey) string {
+	return keyID(publicIDHeader, Public(k))
+}
+
+// SecretID returns the k2.sid key ID for k.
+func SecretID(k paseto.AsymmetricSecretKey) string {
+	return keyID(secretIDHeader, Secret(k))
+}
+
+// keyID implements the PASERK key-ID algorithm: idHeader followed by the
+// base64url encoding of a 33-byte BLAKE2b hash of idHeader || paserk.
+func keyID(idHeader, paserk string) string {
+	// 33 is a valid blake2b output size, so this cannot fail.
+	h, _ := blake2b.New(33, nil)
+	h.Write([]byte(idHeader))
+	h.Write([]byte(paserk))
+	return idHeader + base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
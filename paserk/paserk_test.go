@@ -0,0 +1,119 @@
+package paserk
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/josharian/paseto"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	k, err := paseto.NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Local(k)
+	got, err := ParseLocal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != k {
+		t.Errorf("ParseLocal(Local(k)) = %v, want %v", got, k)
+	}
+}
+
+func TestPublicSecretRoundTrip(t *testing.T) {
+	pub, sec, err := paseto.GenerateAsymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotPub, err := ParsePublic(Public(pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPub != pub {
+		t.Errorf("ParsePublic(Public(k)) = %v, want %v", gotPub, pub)
+	}
+
+	gotSec, err := ParseSecret(Secret(sec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotSec != sec {
+		t.Errorf("ParseSecret(Secret(k)) = %v, want %v", gotSec, sec)
+	}
+}
+
+// TestSerialization_KnownAnswer checks Local/LocalID, Public/PublicID, and
+// Secret/SecretID against the official PASERK k2.local, k2.lid, k2.public,
+// k2.pid, k2.secret, and k2.sid test vectors from
+// https://github.com/paseto-standard/test-vectors/tree/master/PASERK
+// (vectors k2.local-2, k2.lid-2, k2.public-2, k2.pid-2, k2.secret-2, and
+// k2.sid-2), so that a serialization or key-ID bug that happened to be
+// internally self-consistent would still be caught.
+func TestSerialization_KnownAnswer(t *testing.T) {
+	kb, err := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var k paseto.SymmetricKey
+	copy(k[:], kb)
+	if got, want := Local(k), "k2.local.cHFyc3R1dnd4eXp7fH1-f4CBgoOEhYaHiImKi4yNjo8"; got != want {
+		t.Errorf("Local(k) = %q, want %q", got, want)
+	}
+	if got, want := LocalID(k), "k2.lid.keK316jg65NYOw6BbBHJHeQ7YWpyuHfNRxBVtY3kNoXG"; got != want {
+		t.Errorf("LocalID(k) = %q, want %q", got, want)
+	}
+
+	var pub paseto.AsymmetricPublicKey
+	copy(pub[:], kb)
+	if got, want := Public(pub), "k2.public.cHFyc3R1dnd4eXp7fH1-f4CBgoOEhYaHiImKi4yNjo8"; got != want {
+		t.Errorf("Public(pub) = %q, want %q", got, want)
+	}
+	if got, want := PublicID(pub), "k2.pid.4zgEvkSaB64DlcV9ChYZPEqBATLwUsB5zCrlpEOk2wD9"; got != want {
+		t.Errorf("PublicID(pub) = %q, want %q", got, want)
+	}
+
+	sb, err := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f1ce56a48c82ff99162a14bc544612674e5d61fb9317e65d4055780fdbcb4dc35")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sec paseto.AsymmetricSecretKey
+	copy(sec[:], sb)
+	if got, want := Secret(sec), "k2.secret.cHFyc3R1dnd4eXp7fH1-f4CBgoOEhYaHiImKi4yNjo8c5WpIyC_5kWKhS8VEYSZ05dYfuTF-ZdQFV4D9vLTcNQ"; got != want {
+		t.Errorf("Secret(sec) = %q, want %q", got, want)
+	}
+	if got, want := SecretID(sec), "k2.sid.9wfgiRJhydmagHQ9kKOOxQm3OXRTCPxkelCzxw1sJRkV"; got != want {
+		t.Errorf("SecretID(sec) = %q, want %q", got, want)
+	}
+}
+
+// TestParseLocal_KnownAnswerFailures checks the k2.local-fail-1 (too short)
+// and k2.local-fail-2 (wrong version header) vectors from the same suite.
+func TestParseLocal_KnownAnswerFailures(t *testing.T) {
+	if _, err := ParseLocal("k2.local.cHFyc3R1dnd4eXp7fH1-f4CBgoOEhYaHiImKi4yNjp"); err == nil {
+		t.Error("ParseLocal accepted a too-short PASERK")
+	}
+	if _, err := ParseLocal("k1.local.cHFyc3R1dnd4eXp7fH1-f4CBgoOEhYaHiImKi4yNjpA"); err == nil {
+		t.Error("ParseLocal accepted a k1 (wrong version) PASERK")
+	}
+}
+
+func TestKeyIDsAreStableAndDistinct(t *testing.T) {
+	k1, err := paseto.NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := paseto.NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if LocalID(k1) != LocalID(k1) {
+		t.Error("LocalID is not stable")
+	}
+	if LocalID(k1) == LocalID(k2) {
+		t.Error("LocalID collided for distinct keys")
+	}
+}
@@ -0,0 +1,134 @@
+package paseto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func TestEncryptDecryptStream(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testPayload := bytes.Repeat([]byte("large payload "), streamFrameSize/8) // spans several frames
+	testFooter := []byte("footer")
+
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, key, testFooter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(testPayload); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, footer, err := NewDecryptReader(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(footer) != string(testFooter) {
+		t.Errorf("footer %q != %q", string(footer), string(testFooter))
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, testPayload) {
+		t.Error("round trip payload mismatch")
+	}
+}
+
+func TestDecryptStream_Truncated(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(bytes.Repeat([]byte("x"), streamFrameSize+1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	truncated := buf.Bytes()[:buf.Len()-4]
+	r, _, err := NewDecryptReader(bytes.NewReader(truncated), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(r); err == nil {
+		t.Fatal("expected error reading a truncated stream")
+	}
+}
+
+// TestDecryptStream_OversizedFrameLen checks that a frame claiming a huge
+// length is rejected before any allocation is attempted, rather than
+// causing a multi-gigabyte (or, on 32-bit int platforms, negative-length
+// panicking) allocation.
+func TestDecryptStream_OversizedFrameLen(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := buf.Bytes()
+	// The (only) frame's length prefix immediately follows the header,
+	// nonce, and empty footer's length prefix.
+	off := len(v2localStream) + 24 + 4
+	binary.LittleEndian.PutUint32(tampered[off:], 0xFFFFFFF0)
+
+	r, _, err := NewDecryptReader(bytes.NewReader(tampered), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(r); err == nil {
+		t.Fatal("expected error reading a stream with an oversized frame length")
+	}
+}
+
+// TestDecryptStream_OversizedFooterLen checks the analogous case for the
+// footer length prefix.
+func TestDecryptStream_OversizedFooterLen(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, key, []byte("footer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := buf.Bytes()
+	// The footer length prefix immediately follows the header and nonce.
+	off := len(v2localStream) + 24
+	binary.LittleEndian.PutUint32(tampered[off:], 0xFFFFFFF0)
+
+	if _, _, err := NewDecryptReader(bytes.NewReader(tampered), key); err == nil {
+		t.Fatal("expected error for a stream with an oversized footer length")
+	}
+}
@@ -1,4 +1,5 @@
-// Package paseto implements PASETO v2.local.
+// Package paseto implements PASETO v2.local, v2.public, v4.local, and the
+// non-standard streaming extension v2.local-stream.
 //
 // See https://paseto.io/ for details.
 //
@@ -16,22 +17,17 @@ import (
 	"golang.org/x/crypto/chacha20poly1305"
 )
 
-// TODO: accept a *[32]byte key instead of a slice?
-
 var randRead = rand.Read // testing hook for nonce control
 
-func Encrypt(message []byte, key []byte, footer []byte) ([]byte, error) {
+func Encrypt(message []byte, key SymmetricKey, footer []byte) []byte {
 	// https://github.com/paragonie/paseto/blob/master/docs/01-Protocol-Versions/Version2.md#encrypt
 
 	// Change variable names to match the docs.
 	m := message
-	k := key
 	f := footer
 	// Set up our aead object. We'll use it later.
-	aead, err := chacha20poly1305.NewX(k)
-	if err != nil {
-		return nil, err
-	}
+	// key is fixed-size, so this cannot fail.
+	aead, _ := chacha20poly1305.NewX(key[:])
 	// 1. Set header h to v2.local.
 	h := v2local
 	// nc is the concatenation of n and c (to be defined soon).
@@ -41,14 +37,12 @@ func Encrypt(message []byte, key []byte, footer []byte) ([]byte, error) {
 	// 2. Generate 24 random bytes from the OS's CSPRNG.
 	buf := nc[:24]
 	if _, err := randRead(buf); err != nil {
-		return nil, err
+		panic("paseto: failed to read random bytes: " + err.Error())
 	}
 	// 3. Calculate BLAKE2b of the message m with the output of step 2 as the key,
 	// with an output length of 24. This will be our nonce, n.
-	hash, err := blake2b.New(24, buf)
-	if err != nil {
-		return nil, err
-	}
+	// 24 is always a valid blake2b output size, so this cannot fail.
+	hash, _ := blake2b.New(24, buf)
 	hash.Write(m)
 	// Place n at the beginning of nc.
 	// At this point, n == nc.
@@ -82,21 +76,21 @@ func Encrypt(message []byte, key []byte, footer []byte) ([]byte, error) {
 		off++
 		base64.RawURLEncoding.Encode(out[off:], f)
 	}
-	return out, nil
+	return out
 }
 
-func Decrypt(token []byte, key []byte) (payload, footer []byte, ok bool) {
+func Decrypt(token []byte, key SymmetricKey, opts ...Option) (payload, footer []byte, ok bool) {
 	// https://github.com/paragonie/paseto/blob/master/docs/01-Protocol-Versions/Version2.md#decrypt
 
 	// Change variable names to match the docs.
 	m := token
-	k := key
+	o := parseOptions(opts)
 
 	// 1. If f is not empty, implementations MAY verify that the value
 	// appended to the token matches some expected string f,
 	// provided they do so using a constant-time string compare function.
 
-	// We don't verify f.
+	// Verified below, once f has been decoded, via ExpectFooter.
 
 	// 2. Verify that the message begins with v2.local., otherwise throw an exception.
 	// This constant will be referred to as h.
@@ -122,6 +116,9 @@ func Decrypt(token []byte, key []byte) (payload, footer []byte, ok bool) {
 		}
 		m = m[:i]
 	}
+	if !o.check(footer) {
+		return nil, nil, false
+	}
 	raw, ok := decodeBase64(m)
 	if !ok {
 		return nil, nil, false
@@ -139,16 +136,14 @@ func Decrypt(token []byte, key []byte) (payload, footer []byte, ok bool) {
 	//          nonce = n
 	//          key = k
 	//       );
-	aead, err := chacha20poly1305.NewX(k)
-	if err != nil {
-		return nil, nil, false
-	}
+	// key is fixed-size, so this cannot fail.
+	aead, _ := chacha20poly1305.NewX(key[:])
 
 	// It is tempting to optimize by passing in a buffer here.
 	// But we have to take care: We return payload and footer to the caller,
 	// so we don't want either of them to pin a lot of extra memory, or leak anything.
 	// For now, be conservative and start from scratch.
-	payload, err = aead.Open(nil, n, c, preAuth)
+	payload, err := aead.Open(nil, n, c, preAuth)
 	if err != nil {
 		return nil, nil, false
 	}
@@ -157,9 +152,14 @@ func Decrypt(token []byte, key []byte) (payload, footer []byte, ok bool) {
 	return payload, footer, true
 }
 
+// base64Strict rejects encodings with non-zero unused bits in their final
+// character, so that two different token strings can never decode to the
+// same bytes (base64.RawURLEncoding alone silently accepts both).
+var base64Strict = base64.RawURLEncoding.Strict()
+
 func decodeBase64(src []byte) ([]byte, bool) {
-	dst := make([]byte, base64.RawURLEncoding.DecodedLen(len(src)))
-	n, err := base64.RawURLEncoding.Decode(dst, src)
+	dst := make([]byte, base64Strict.DecodedLen(len(src)))
+	n, err := base64Strict.Decode(dst, src)
 	if err != nil {
 		return nil, false
 	}
@@ -12,17 +12,27 @@ import (
 // The rationale behind borrowing them was to attempt to ensure compatibility,
 // and to make benchmark apples-to-apples (as much as possible).
 
+func mustSymmetricKey(t *testing.T, b []byte) SymmetricKey {
+	t.Helper()
+	var k SymmetricKey
+	if n := copy(k[:], b); n != len(k) {
+		t.Fatalf("key is %d bytes, want %d", len(b), len(k))
+	}
+	return k
+}
+
 func TestPasetoV2_Encrypt_Compatibility(t *testing.T) {
-	nullKey := bytes.Repeat([]byte{0}, 32)
-	fullKey := bytes.Repeat([]byte{0xff}, 32)
-	symmetricKey, _ := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	nullKey := mustSymmetricKey(t, bytes.Repeat([]byte{0}, 32))
+	fullKey := mustSymmetricKey(t, bytes.Repeat([]byte{0xff}, 32))
+	symmetricKeyBytes, _ := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	symmetricKey := mustSymmetricKey(t, symmetricKeyBytes)
 	nonce := bytes.Repeat([]byte{0}, 24)
 	nonce2, _ := hex.DecodeString("45742c976d684ff84ebdc0de59809a97cda2f64c84fda19b")
 	footer := []byte("Cuon Alpinus")
 	payload := []byte("Love is stronger than hate or fear")
 
 	cases := map[string]struct {
-		key     []byte
+		key     SymmetricKey
 		token   string
 		nonce   []byte
 		payload []byte
@@ -105,10 +115,7 @@ func TestPasetoV2_Encrypt_Compatibility(t *testing.T) {
 	for name, test := range cases {
 		t.Run(name, func(t *testing.T) {
 			randRead = bytes.NewBuffer(test.nonce).Read
-			token, err := Encrypt(test.payload, test.key, test.footer)
-			if err != nil {
-				t.Fatal(err)
-			}
+			token := Encrypt(test.payload, test.key, test.footer)
 			if test.token != string(token) {
 				t.Fatalf("%q != %q", test.token, string(token))
 			}
@@ -118,15 +125,13 @@ func TestPasetoV2_Encrypt_Compatibility(t *testing.T) {
 }
 
 func TestEncryptDecrypt(t *testing.T) {
-	key, _ := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	keyBytes, _ := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	key := mustSymmetricKey(t, keyBytes)
 
 	testPayload := []byte("payload")
 	testFooter := []byte("footer")
 
-	token, err := Encrypt(testPayload, key, testFooter)
-	if err != nil {
-		t.Fatal(err)
-	}
+	token := Encrypt(testPayload, key, testFooter)
 	obtainedPayload, obtainedFooter, ok := Decrypt(token, key)
 	if !ok {
 		t.Fatal("round trip failed")
@@ -140,7 +145,8 @@ func TestEncryptDecrypt(t *testing.T) {
 }
 
 func Benchmark_V2_String_Encrypt(b *testing.B) {
-	symmetricKey := []byte("YELLOW SUBMARINE, BLACK WIZARDRY")
+	var symmetricKey SymmetricKey
+	copy(symmetricKey[:], "YELLOW SUBMARINE, BLACK WIZARDRY")
 
 	var (
 		payload = []byte("payload")
@@ -149,12 +155,13 @@ func Benchmark_V2_String_Encrypt(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = Encrypt(payload, symmetricKey, footer)
+		_ = Encrypt(payload, symmetricKey, footer)
 	}
 }
 
 func Benchmark_V2_String_Decrypt(b *testing.B) {
-	symmetricKey := []byte("YELLOW SUBMARINE, BLACK WIZARDRY")
+	var symmetricKey SymmetricKey
+	copy(symmetricKey[:], "YELLOW SUBMARINE, BLACK WIZARDRY")
 	token := []byte("v2.local.VxvYfYL-KSCBaNC8toZUWgoqYHveHjypGx87pqUi0e69gKNAApe3sVkAog30zAc.Zm9vdGVy")
 
 	b.ResetTimer()
@@ -0,0 +1,125 @@
+package paseto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+)
+
+// Sign signs message using the Ed25519 private key privateKey, producing a
+// v2.public token. footer, if non-empty, is authenticated but not encrypted,
+// and is appended to the token in cleartext.
+func Sign(message []byte, privateKey AsymmetricSecretKey, footer []byte) ([]byte, error) {
+	// https://github.com/paragonie/paseto/blob/master/docs/01-Protocol-Versions/Version2.md#sign
+
+	// Change variable names to match the docs.
+	m := message
+	f := footer
+
+	// 1. Set header h to v2.public.
+	h := v2public
+
+	// 2. Pack h, m, and f together (in that order) using PAE.
+	m2 := pae(h, m, f)
+
+	// 3. Sign m2 using Ed25519, using sk as the private key. We'll call the
+	// output of this signature sig.
+	//    sig = crypto_sign_detached(
+	//       message = m2
+	//       private_key = sk
+	//    );
+	sig := ed25519.Sign(ed25519.PrivateKey(privateKey[:]), m2)
+
+	// 4. If f is:
+	//      Empty: return h || b64(m || sig)
+	//      Non-empty: return h || b64(m || sig) || . || base64url(f)
+	//      ...where || means "concatenate"
+	ms := make([]byte, 0, len(m)+len(sig))
+	ms = append(ms, m...)
+	ms = append(ms, sig...)
+
+	outlen := len(h) + base64.RawURLEncoding.EncodedLen(len(ms))
+	if len(f) > 0 {
+		outlen += 1 + base64.RawURLEncoding.EncodedLen(len(f)) // 1 for '.'
+	}
+	out := make([]byte, outlen)
+	off := copy(out, h)
+	base64.RawURLEncoding.Encode(out[off:], ms)
+	off += base64.RawURLEncoding.EncodedLen(len(ms))
+	if len(f) > 0 {
+		out[off] = '.'
+		off++
+		base64.RawURLEncoding.Encode(out[off:], f)
+	}
+	return out, nil
+}
+
+// Verify verifies a v2.public token using the Ed25519 public key publicKey,
+// returning the signed payload and footer if the signature is valid.
+func Verify(token []byte, publicKey AsymmetricPublicKey, opts ...Option) (payload, footer []byte, ok bool) {
+	// https://github.com/paragonie/paseto/blob/master/docs/01-Protocol-Versions/Version2.md#verify
+
+	// Change variable names to match the docs.
+	m := token
+	o := parseOptions(opts)
+
+	// 1. If f is not empty, implementations MAY verify that the value
+	// appended to the token matches some expected string f,
+	// provided they do so using a constant-time string compare function.
+
+	// Verified below, once f has been decoded, via ExpectFooter.
+
+	// 2. Verify that the message begins with v2.public., otherwise throw an
+	// exception. This constant will be referred to as h.
+	h := v2public
+	if !bytes.HasPrefix(m, h) {
+		return nil, nil, false
+	}
+	m = m[len(h):]
+
+	// 3. Decode the payload (m sans h, f, and the optional trailing period
+	// between m and f) from base64url to raw binary.
+
+	// There should be at most one "." in m, separating the payload and the
+	// footer. Both payload and footer are base64-encoded, and "." is not a
+	// part of that encoding. So here we simply decide where to split, and
+	// let base64 decoding detect any extraneous ".".
+	if i := bytes.IndexByte(m, '.'); i >= 0 {
+		// Footer is present. Decode it. And adjust m.
+		footer, ok = decodeBase64(m[i+1:])
+		if !ok {
+			return nil, nil, false
+		}
+		m = m[:i]
+	}
+	if !o.check(footer) {
+		return nil, nil, false
+	}
+	raw, ok := decodeBase64(m)
+	if !ok {
+		return nil, nil, false
+	}
+	if len(raw) < ed25519.SignatureSize {
+		return nil, nil, false
+	}
+	s := raw[len(raw)-ed25519.SignatureSize:]
+	payload = raw[:len(raw)-ed25519.SignatureSize]
+
+	// 4. Pack h, m, and f together (in that order) using PAE.
+	m2 := pae(h, payload, footer)
+
+	// 5. Use Ed25519 to verify that the signature is valid for m2.
+	//    valid = crypto_sign_verify_detached(
+	//       signature = s
+	//       message = m2
+	//       public_key = pk
+	//    );
+	if !ed25519.Verify(ed25519.PublicKey(publicKey[:]), m2, s) {
+		return nil, nil, false
+	}
+
+	// 6. If the signature is invalid, throw an exception. Otherwise, return m.
+	return payload, footer, true
+}
+
+var v2public = []byte("v2.public.")
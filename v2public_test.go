@@ -0,0 +1,46 @@
+package paseto
+
+import (
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	publicKey, privateKey, err := GenerateAsymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testPayload := []byte("payload")
+	testFooter := []byte("footer")
+
+	token, err := Sign(testPayload, privateKey, testFooter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obtainedPayload, obtainedFooter, ok := Verify(token, publicKey)
+	if !ok {
+		t.Fatal("verify failed")
+	}
+	if string(testPayload) != string(obtainedPayload) {
+		t.Errorf("payload %q != %q", string(testPayload), string(obtainedPayload))
+	}
+	if string(testFooter) != string(obtainedFooter) {
+		t.Errorf("footer %q != %q", string(testFooter), string(obtainedFooter))
+	}
+}
+
+func TestVerify_TamperedMessage(t *testing.T) {
+	publicKey, privateKey, err := GenerateAsymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := Sign([]byte("payload"), privateKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token = append(token, 'x')
+	if _, _, ok := Verify(token, publicKey); ok {
+		t.Fatal("verify succeeded on tampered token")
+	}
+}
@@ -0,0 +1,160 @@
+package paseto
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+// Domain-separation strings used to derive the v4.local encryption and
+// authentication keys from the symmetric key, per the PASETO v4 spec.
+// https://github.com/paseto-standard/paseto-spec/blob/master/docs/01-Protocol-Versions/Version4.md#encrypt
+var (
+	v4EncryptionKeyDomain = []byte("paseto-encryption-key")
+	v4AuthKeyDomain       = []byte("paseto-auth-key-for-aead")
+)
+
+// EncryptV4 encrypts message using PASETO v4.local: key k, split into an
+// XChaCha20 encryption key and nonce plus a BLAKE2b authentication key
+// derived from k and a random 32-byte nonce n. footer, if non-empty, is
+// authenticated but not encrypted, and is appended to the token in
+// cleartext. implicit is authenticated but included in neither the token
+// nor its footer; the same value must be supplied to DecryptV4 to recover
+// the payload.
+func EncryptV4(message []byte, k SymmetricKey, footer, implicit []byte) []byte {
+	// https://github.com/paseto-standard/paseto-spec/blob/master/docs/01-Protocol-Versions/Version4.md#encrypt
+
+	m := message
+	f := footer
+	i := implicit
+	h := v4local
+
+	// 1. Generate 32 random bytes from the OS's CSPRNG. This is n.
+	n := make([]byte, 32)
+	if _, err := randRead(n); err != nil {
+		panic("paseto: failed to read random bytes: " + err.Error())
+	}
+
+	// 2. Derive Ek and n2 from k and n, and Ak from k and n.
+	ek, n2, ak := v4DeriveKeys(k, n)
+
+	// 3. Encrypt m with XChaCha20, using n2 as the nonce and Ek as the key.
+	c := make([]byte, len(m))
+	stream, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		panic("paseto: " + err.Error())
+	}
+	stream.XORKeyStream(c, m)
+
+	// 4. Pack h, n, c, f, and i together (in that order) using PAE. Compute
+	// t as keyed BLAKE2b-MAC of the result, keyed with Ak.
+	preAuth := pae(h, n, c, f, i)
+	t := v4Tag(ak, preAuth)
+
+	// 5. If f is:
+	//      Empty: return h || b64(n || c || t)
+	//      Non-empty: return h || b64(n || c || t) || . || base64url(f)
+	nct := make([]byte, 0, len(n)+len(c)+len(t))
+	nct = append(nct, n...)
+	nct = append(nct, c...)
+	nct = append(nct, t...)
+
+	outlen := len(h) + base64.RawURLEncoding.EncodedLen(len(nct))
+	if len(f) > 0 {
+		outlen += 1 + base64.RawURLEncoding.EncodedLen(len(f))
+	}
+	out := make([]byte, outlen)
+	off := copy(out, h)
+	base64.RawURLEncoding.Encode(out[off:], nct)
+	off += base64.RawURLEncoding.EncodedLen(len(nct))
+	if len(f) > 0 {
+		out[off] = '.'
+		off++
+		base64.RawURLEncoding.Encode(out[off:], f)
+	}
+	return out
+}
+
+// DecryptV4 decrypts a PASETO v4.local token produced by EncryptV4. implicit
+// must match the value passed to EncryptV4.
+func DecryptV4(token []byte, k SymmetricKey, implicit []byte, opts ...Option) (payload, footer []byte, ok bool) {
+	// https://github.com/paseto-standard/paseto-spec/blob/master/docs/01-Protocol-Versions/Version4.md#decrypt
+
+	m := token
+	i := implicit
+	o := parseOptions(opts)
+
+	h := v4local
+	if !bytes.HasPrefix(m, h) {
+		return nil, nil, false
+	}
+	m = m[len(h):]
+
+	if idx := bytes.IndexByte(m, '.'); idx >= 0 {
+		footer, ok = decodeBase64(m[idx+1:])
+		if !ok {
+			return nil, nil, false
+		}
+		m = m[:idx]
+	}
+	if !o.check(footer) {
+		return nil, nil, false
+	}
+	raw, ok := decodeBase64(m)
+	if !ok {
+		return nil, nil, false
+	}
+	if len(raw) < 32+blake2b.Size256 {
+		return nil, nil, false
+	}
+	n := raw[:32]
+	c := raw[32 : len(raw)-blake2b.Size256]
+	t := raw[len(raw)-blake2b.Size256:]
+
+	ek, n2, ak := v4DeriveKeys(k, n)
+
+	preAuth := pae(h, n, c, footer, i)
+	expected := v4Tag(ak, preAuth)
+	if subtle.ConstantTimeCompare(t, expected) != 1 {
+		return nil, nil, false
+	}
+
+	payload = make([]byte, len(c))
+	stream, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		return nil, nil, false
+	}
+	stream.XORKeyStream(payload, c)
+	return payload, footer, true
+}
+
+// v4DeriveKeys splits BLAKE2b-56(key=k, msg="paseto-encryption-key" || n)
+// into a 32-byte encryption key and a 24-byte XChaCha20 nonce, and computes
+// Ak = BLAKE2b-32(key=k, msg="paseto-auth-key-for-aead" || n).
+func v4DeriveKeys(k SymmetricKey, n []byte) (ek, n2 []byte, ak []byte) {
+	// 56 is a valid blake2b output size and k is a valid blake2b key, so
+	// this cannot fail.
+	h, _ := blake2b.New(56, k[:])
+	h.Write(v4EncryptionKeyDomain)
+	h.Write(n)
+	tmp := h.Sum(nil)
+	ek, n2 = tmp[:32], tmp[32:]
+
+	a, _ := blake2b.New256(k[:])
+	a.Write(v4AuthKeyDomain)
+	a.Write(n)
+	ak = a.Sum(nil)
+	return ek, n2, ak
+}
+
+// v4Tag computes the keyed BLAKE2b-MAC of preAuth, using ak as the key.
+func v4Tag(ak, preAuth []byte) []byte {
+	h, _ := blake2b.New256(ak)
+	h.Write(preAuth)
+	return h.Sum(nil)
+}
+
+var v4local = []byte("v4.local.")
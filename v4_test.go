@@ -0,0 +1,230 @@
+package paseto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+// TestEncryptV4_Compatibility and TestDecryptV4_Compatibility use the
+// official v4.local test vectors from
+// https://github.com/paseto-standard/test-vectors/blob/master/v4.json
+// (vectors 4-E-1 through 4-E-9 and 4-F-2/4-F-4/4-F-5), rather than
+// round-tripping this package's own EncryptV4/DecryptV4 against each other, so
+// that a derivation bug that happened to be internally self-consistent
+// would still be caught.
+
+func TestEncryptV4_Compatibility(t *testing.T) {
+	keyBytes, _ := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	key := mustSymmetricKey(t, keyBytes)
+	zeroNonce, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000000")
+	nonce, _ := hex.DecodeString("df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8")
+	kidFooter := []byte(`{"kid":"zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN"}`)
+
+	secretMessage := []byte(`{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`)
+	hiddenMessage := []byte(`{"data":"this is a hidden message","exp":"2022-01-01T00:00:00+00:00"}`)
+
+	cases := map[string]struct {
+		nonce    []byte
+		payload  []byte
+		footer   []byte
+		implicit []byte
+		token    string
+	}{
+		"4-E-1": {
+			nonce:   zeroNonce,
+			payload: secretMessage,
+			token:   "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg",
+		},
+		"4-E-2": {
+			nonce:   zeroNonce,
+			payload: hiddenMessage,
+			token:   "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvS2csCgglvpk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XIemu9chy3WVKvRBfg6t8wwYHK0ArLxxfZP73W_vfwt5A",
+		},
+		"4-E-3": {
+			nonce:   nonce,
+			payload: secretMessage,
+			token:   "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6-tyebyWG6Ov7kKvBdkrrAJ837lKP3iDag2hzUPHuMKA",
+		},
+		"4-E-4": {
+			nonce:   nonce,
+			payload: hiddenMessage,
+			token:   "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t4gt6TiLm55vIH8c_lGxxZpE3AWlH4WTR0v45nsWoU3gQ",
+		},
+		"4-E-5": {
+			nonce:   nonce,
+			payload: secretMessage,
+			footer:  kidFooter,
+			token:   "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t4x-RMNXtQNbz7FvFZ_G-lFpk5RG3EOrwDL6CgDqcerSQ.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		"4-E-6": {
+			nonce:   nonce,
+			payload: hiddenMessage,
+			footer:  kidFooter,
+			token:   "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6pWSA5HX2wjb3P-xLQg5K5feUCX4P2fpVK3ZLWFbMSxQ.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		"4-E-7": {
+			nonce:    nonce,
+			payload:  secretMessage,
+			footer:   kidFooter,
+			implicit: []byte(`{"test-vector":"4-E-7"}`),
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t40KCCWLA7GYL9KFHzKlwY9_RnIfRrMQpueydLEAZGGcA.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		"4-E-8": {
+			nonce:    nonce,
+			payload:  hiddenMessage,
+			footer:   kidFooter,
+			implicit: []byte(`{"test-vector":"4-E-8"}`),
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t5uvqQbMGlLLNYBc7A6_x7oqnpUK5WLvj24eE4DVPDZjw.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		"4-E-9": {
+			nonce:    nonce,
+			payload:  hiddenMessage,
+			footer:   []byte("arbitrary-string-that-isn't-json"),
+			implicit: []byte(`{"test-vector":"4-E-9"}`),
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6tybdlmnMwcDMw0YxA_gFSE_IUWl78aMtOepFYSWYfQA.YXJiaXRyYXJ5LXN0cmluZy10aGF0LWlzbid0LWpzb24",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			randRead = bytes.NewBuffer(test.nonce).Read
+			token := EncryptV4(test.payload, key, test.footer, test.implicit)
+			if test.token != string(token) {
+				t.Fatalf("%q != %q", test.token, string(token))
+			}
+		})
+	}
+	randRead = rand.Read
+}
+
+// TestDecryptV4_Compatibility decrypts three of the official tokens used by
+// TestEncryptV4_Compatibility (4-E-1, 4-E-7, 4-E-9), plus a handful of the
+// suite's expect-fail
+// vectors (invalid tag, truncated payload, and malformed base64), without
+// needing to control the nonce.
+func TestDecryptV4_Compatibility(t *testing.T) {
+	keyBytes, _ := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	key := mustSymmetricKey(t, keyBytes)
+	kidFooter := []byte(`{"kid":"zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN"}`)
+
+	secretMessage := []byte(`{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`)
+	hiddenMessage := []byte(`{"data":"this is a hidden message","exp":"2022-01-01T00:00:00+00:00"}`)
+
+	okCases := map[string]struct {
+		token    string
+		payload  []byte
+		footer   []byte
+		implicit []byte
+	}{
+		"4-E-1": {
+			token:   "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg",
+			payload: secretMessage,
+		},
+		"4-E-7": {
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t40KCCWLA7GYL9KFHzKlwY9_RnIfRrMQpueydLEAZGGcA.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+			payload:  secretMessage,
+			footer:   kidFooter,
+			implicit: []byte(`{"test-vector":"4-E-7"}`),
+		},
+		"4-E-9": {
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6tybdlmnMwcDMw0YxA_gFSE_IUWl78aMtOepFYSWYfQA.YXJiaXRyYXJ5LXN0cmluZy10aGF0LWlzbid0LWpzb24",
+			payload:  hiddenMessage,
+			footer:   []byte("arbitrary-string-that-isn't-json"),
+			implicit: []byte(`{"test-vector":"4-E-9"}`),
+		},
+	}
+	for name, test := range okCases {
+		t.Run(name, func(t *testing.T) {
+			payload, footer, ok := DecryptV4([]byte(test.token), key, test.implicit)
+			if !ok {
+				t.Fatal("decryption failed")
+			}
+			if string(payload) != string(test.payload) {
+				t.Errorf("payload %q != %q", payload, test.payload)
+			}
+			if string(footer) != string(test.footer) {
+				t.Errorf("footer %q != %q", footer, test.footer)
+			}
+		})
+	}
+
+	// 4-F-2: v4.public token fed to DecryptV4.
+	// 4-F-4: 4-E-1 with nonzero unused bits in its last base64 character;
+	// it decodes to the same bytes unless base64 decoding is strict.
+	// 4-F-5: un-base64url-encoded (padded) payload.
+	failCases := map[string]struct {
+		token    string
+		implicit []byte
+	}{
+		"4-F-2": {
+			token:    "v4.public.eyJpbnZhbGlkIjoidGhpcyBzaG91bGQgbmV2ZXIgZGVjb2RlIn22Sp4gjCaUw0c7EH84ZSm_jN_Qr41MrgLNu5LIBCzUr1pn3Z-Wukg9h3ceplWigpoHaTLcwxj0NsI1vjTh67YB.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+			implicit: []byte(`{"test-vector":"4-F-2"}`),
+		},
+		"4-F-4": {
+			token: "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQh",
+		},
+		"4-F-5": {
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t4x-RMNXtQNbz7FvFZ_G-lFpk5RG3EOrwDL6CgDqcerSQ==.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+			implicit: []byte(`{"test-vector":"4-F-5"}`),
+		},
+	}
+	for name, test := range failCases {
+		t.Run(name, func(t *testing.T) {
+			if _, _, ok := DecryptV4([]byte(test.token), key, test.implicit); ok {
+				t.Fatal("expected decryption to fail")
+			}
+		})
+	}
+}
+
+func TestEncryptDecryptV4(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testPayload := []byte("payload")
+	testFooter := []byte("footer")
+	testImplicit := []byte("implicit")
+
+	token := EncryptV4(testPayload, key, testFooter, testImplicit)
+	obtainedPayload, obtainedFooter, ok := DecryptV4(token, key, testImplicit)
+	if !ok {
+		t.Fatal("round trip failed")
+	}
+	if string(testPayload) != string(obtainedPayload) {
+		t.Errorf("payload %q != %q", string(testPayload), string(obtainedPayload))
+	}
+	if string(testFooter) != string(obtainedFooter) {
+		t.Errorf("footer %q != %q", string(testFooter), string(obtainedFooter))
+	}
+}
+
+func TestDecryptV4_WrongImplicit(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := EncryptV4([]byte("payload"), key, nil, []byte("implicit"))
+	if _, _, ok := DecryptV4(token, key, []byte("different")); ok {
+		t.Fatal("expected failure with mismatched implicit assertion")
+	}
+}
+
+func TestDecryptV4_ExpectFooter(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := EncryptV4([]byte("payload"), key, []byte("key-id:1"), nil)
+	if _, _, ok := DecryptV4(token, key, nil, ExpectFooter([]byte("key-id:1"))); !ok {
+		t.Fatal("expected success with matching footer")
+	}
+	if _, _, ok := DecryptV4(token, key, nil, ExpectFooter([]byte("key-id:2"))); ok {
+		t.Fatal("expected failure with mismatched footer")
+	}
+}